@@ -0,0 +1,104 @@
+// Package buflog is a log.Logger implementation that keeps events in
+// memory until one reaches a configured flush threshold, at which point it
+// writes out everything buffered so far plus every future event. It backs
+// stdlog's -flushlog flag, e.g. to only mail a cron job's logs when one of
+// them is an error.
+package buflog
+
+import (
+	"io"
+	"sync"
+
+	"github.com/mehrvarz/log"
+	"github.com/mehrvarz/log/golog"
+)
+
+type entry struct {
+	level log.Level
+	line  []byte
+}
+
+// core holds the state shared by a Logger and every child created from it
+// via With, so buffered events from both end up interleaved in the same
+// buffer under the same lock.
+type core struct {
+	mu             sync.Mutex
+	out            io.Writer
+	flushThreshold log.Level
+	flushed        bool
+	buffer         []entry
+}
+
+// Logger is a log.Logger that buffers events until flushThreshold is
+// reached. See the package doc.
+type Logger struct {
+	c     *core
+	inner *golog.Logger
+}
+
+// New returns a Logger that writes events at or above threshold to out,
+// buffering them in memory until one reaches flushThreshold.
+func New(out io.Writer, threshold, flushThreshold log.Level) *Logger {
+	return NewFormatted(out, threshold, flushThreshold, golog.TextFormatter{})
+}
+
+// NewFormatted is like New but renders events with formatter instead of the
+// default human-readable text line, so a flushlog-enabled logger still
+// honors e.g. -logformat=json.
+func NewFormatted(out io.Writer, threshold, flushThreshold log.Level, formatter golog.Formatter) *Logger {
+	c := &core{out: out, flushThreshold: flushThreshold}
+	l := &Logger{c: c}
+	l.inner = golog.NewFormattedWriter(out, threshold, formatter, l.write)
+	return l
+}
+
+func (l *Logger) write(out io.Writer, line []byte, level log.Level) {
+	c := l.c
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.flushed {
+		out.Write(line)
+		return
+	}
+
+	c.buffer = append(c.buffer, entry{level: level, line: append([]byte(nil), line...)})
+	if level != golog.FeedbackLevel && level >= c.flushThreshold {
+		c.flushed = true
+		for _, e := range c.buffer {
+			out.Write(e.line)
+		}
+		c.buffer = nil
+	}
+}
+
+// With returns a child logger that carries key alongside every future
+// event. The child shares its buffer and flush state with l.
+func (l *Logger) With(key string, value interface{}) log.Logger {
+	return &Logger{c: l.c, inner: l.inner.With(key, value).(*golog.Logger)}
+}
+
+// Counts and Suppressed forward to the underlying golog.Logger, whose
+// per-level counters are process-wide (see golog.Logger.Counts).
+func (l *Logger) Counts() [log.None]uint64     { return l.inner.Counts() }
+func (l *Logger) Suppressed() [log.None]uint64 { return l.inner.Suppressed() }
+
+// Feedback and Feedbackln forward to the underlying golog.Logger, which
+// routes the plain line through l.write just like a leveled event, so it
+// lands in the same buffer at the same position relative to surrounding
+// events and survives a replay triggered by one of them.
+func (l *Logger) Feedback(format string, args ...interface{}) { l.inner.Feedback(format, args...) }
+func (l *Logger) Feedbackln(args ...interface{})              { l.inner.Feedbackln(args...) }
+
+// Flush and Close forward to the underlying golog.Logger.
+func (l *Logger) Flush() error { return l.inner.Flush() }
+func (l *Logger) Close() error { return l.inner.Close() }
+
+func (l *Logger) Debug(format string, args ...interface{})     { l.inner.Debug(format, args...) }
+func (l *Logger) Info(format string, args ...interface{})      { l.inner.Info(format, args...) }
+func (l *Logger) Notice(format string, args ...interface{})    { l.inner.Notice(format, args...) }
+func (l *Logger) Warning(format string, args ...interface{})   { l.inner.Warning(format, args...) }
+func (l *Logger) Error(format string, args ...interface{})     { l.inner.Error(format, args...) }
+func (l *Logger) Critical(format string, args ...interface{})  { l.inner.Critical(format, args...) }
+func (l *Logger) Alert(format string, args ...interface{})     { l.inner.Alert(format, args...) }
+func (l *Logger) Emergency(format string, args ...interface{}) { l.inner.Emergency(format, args...) }
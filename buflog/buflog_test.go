@@ -0,0 +1,34 @@
+package buflog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/mehrvarz/log"
+)
+
+func TestNewFlushesBufferOnThreshold(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(&buf, log.Debug, log.Error)
+
+	l.Info("buffered one")
+	l.Info("buffered two")
+	if buf.Len() != 0 {
+		t.Fatalf("events below flushThreshold were written early: %q", buf.String())
+	}
+
+	l.Error("trigger flush")
+	out := buf.String()
+	for _, want := range []string{"buffered one", "buffered two", "trigger flush"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("flushed output %q missing %q", out, want)
+		}
+	}
+
+	buf.Reset()
+	l.Info("after flush")
+	if !strings.Contains(buf.String(), "after flush") {
+		t.Errorf("event logged after flush was not written immediately: %q", buf.String())
+	}
+}
@@ -0,0 +1,40 @@
+package buflog_test
+
+// This file lives in the buflog_test package (rather than buflog, like the
+// rest of this package's tests) because the JSON caller field is computed
+// by walking past call frames inside package buflog itself; a test calling
+// in from package buflog would be misidentified as one of those frames.
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/mehrvarz/log"
+	"github.com/mehrvarz/log/buflog"
+	"github.com/mehrvarz/log/golog"
+)
+
+func TestNewFormattedJSONCallerPointsAtUserCodeNotBuflog(t *testing.T) {
+	var buf bytes.Buffer
+	l := buflog.NewFormatted(&buf, log.Debug, log.Debug, golog.JSONFormatter{})
+
+	_, _, wantLine, _ := runtime.Caller(0)
+	l.Error("boom") // must be the very next line, to match wantLine+1
+
+	var event map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &event); err != nil {
+		t.Fatalf("unmarshal event: %v, line: %q", err, buf.String())
+	}
+	caller, _ := event["caller"].(string)
+	if strings.Contains(caller, "buflog.go") {
+		t.Fatalf("caller = %q, points inside buflog instead of the call site", caller)
+	}
+	want := fmt.Sprintf("caller_test.go:%d", wantLine+1)
+	if !strings.HasSuffix(caller, want) {
+		t.Errorf("caller = %q, want it to end with %s", caller, want)
+	}
+}
@@ -0,0 +1,166 @@
+package golog
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+
+	"github.com/mehrvarz/log"
+)
+
+// OnFull selects what a Logger created with NewAsync does when its buffer
+// is full.
+type OnFull int
+
+const (
+	// Block makes the caller wait for room in the buffer, same as the
+	// synchronous path would.
+	Block OnFull = iota
+	// DropOldest discards the longest-queued buffered event to make room
+	// for the new one.
+	DropOldest
+	// DropNewest discards the incoming event, keeping the buffer as is.
+	DropNewest
+	// WriteSyncFallback writes the incoming event directly to the
+	// destination, bypassing the buffer, instead of dropping it.
+	WriteSyncFallback
+)
+
+type asyncEvent struct {
+	level log.Level
+	line  []byte
+}
+
+// async dispatches writes through ch and a single background goroutine
+// instead of the mutex-guarded synchronous path, so producers do not block
+// on a slow out while the buffer has room. Once it is full, a producer
+// waits under Block and writes out itself under WriteSyncFallback.
+type async struct {
+	c      *core
+	ch     chan asyncEvent
+	onFull OnFull
+
+	flushCh   chan chan struct{}
+	closeCh   chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+}
+
+// NewAsync is like New, except writes are dispatched through a channel of
+// capacity bufSize and a background goroutine instead of holding the
+// caller on a mutex for the duration of the write to out. bufSize=0 makes
+// it behave exactly like New, the synchronous mutex-per-write path; it is
+// the special case of this constructor with nothing to buffer. onFull
+// selects what happens when the buffer is full.
+func NewAsync(out io.Writer, threshold log.Level, bufSize int, onFull OnFull) *Logger {
+	l := New(out, threshold)
+	if bufSize <= 0 {
+		return l
+	}
+
+	a := &async{
+		c:       l.c,
+		ch:      make(chan asyncEvent, bufSize),
+		onFull:  onFull,
+		flushCh: make(chan chan struct{}),
+		closeCh: make(chan struct{}),
+	}
+	l.c.async = a
+	a.wg.Add(1)
+	go a.run()
+	return l
+}
+
+func (a *async) enqueue(level log.Level, line []byte) {
+	ev := asyncEvent{level: level, line: line}
+	switch a.onFull {
+	case DropNewest:
+		select {
+		case a.ch <- ev:
+		default:
+			a.countDropped(ev.level)
+		}
+	case DropOldest:
+		for {
+			select {
+			case a.ch <- ev:
+				return
+			default:
+			}
+			select {
+			case old := <-a.ch:
+				a.countDropped(old.level)
+			default:
+			}
+		}
+	case WriteSyncFallback:
+		select {
+		case a.ch <- ev:
+		default:
+			a.writeLocked(ev)
+		}
+	default: // Block
+		a.ch <- ev
+	}
+}
+
+// writeLocked calls a.c.write under a.c.mu, so a WriteSyncFallback write on
+// the producer's goroutine can never race with run/drain writing a queued
+// event on the background goroutine. The event is only counted as emitted
+// here, once it is actually written, not when it is enqueued.
+func (a *async) writeLocked(ev asyncEvent) {
+	a.c.mu.Lock()
+	defer a.c.mu.Unlock()
+	a.c.write(a.c.out, ev.line, ev.level)
+	if ev.level >= log.Debug {
+		atomic.AddUint64(&eventCounts[ev.level], 1)
+	}
+}
+
+// countDropped records an event discarded by a DropOldest/DropNewest policy
+// instead of being written, so it is reflected in Dropped rather than
+// silently inflating Counts.
+func (a *async) countDropped(level log.Level) {
+	if level >= log.Debug {
+		atomic.AddUint64(&droppedCounts[level], 1)
+	}
+}
+
+func (a *async) run() {
+	defer a.wg.Done()
+	for {
+		select {
+		case ev := <-a.ch:
+			a.writeLocked(ev)
+		case ack := <-a.flushCh:
+			a.drain()
+			close(ack)
+		case <-a.closeCh:
+			a.drain()
+			return
+		}
+	}
+}
+
+// drain writes every event currently queued, without blocking for more.
+func (a *async) drain() {
+	for {
+		select {
+		case ev := <-a.ch:
+			a.writeLocked(ev)
+		default:
+			return
+		}
+	}
+}
+
+func (a *async) flush() {
+	ack := make(chan struct{})
+	a.flushCh <- ack
+	<-ack
+}
+
+func (a *async) stop() {
+	a.closeOnce.Do(func() { close(a.closeCh) })
+	a.wg.Wait()
+}
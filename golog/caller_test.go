@@ -0,0 +1,36 @@
+package golog_test
+
+// This file lives in the golog_test package (rather than golog, like the
+// rest of this package's tests) because the JSON caller field is computed
+// by walking past call frames inside package golog itself; a test calling
+// in from package golog would be misidentified as one of those frames.
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/mehrvarz/log"
+	"github.com/mehrvarz/log/golog"
+)
+
+func TestJSONFormatterCallerPointsAtCallSite(t *testing.T) {
+	var buf bytes.Buffer
+	l := golog.NewFormatted(&buf, log.Debug, golog.JSONFormatter{})
+
+	_, _, wantLine, _ := runtime.Caller(0)
+	l.Error("boom") // must be the very next line, to match wantLine+1
+
+	var event map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &event); err != nil {
+		t.Fatalf("unmarshal event: %v, line: %q", err, buf.String())
+	}
+	caller, _ := event["caller"].(string)
+	want := fmt.Sprintf("caller_test.go:%d", wantLine+1)
+	if !strings.HasSuffix(caller, want) {
+		t.Errorf("caller = %q, want it to end with %s", caller, want)
+	}
+}
@@ -0,0 +1,65 @@
+package golog
+
+import (
+	"expvar"
+	"sync/atomic"
+
+	"github.com/mehrvarz/log"
+)
+
+// eventCounts and suppressedCounts are process-wide: every golog Logger in
+// the process increments the same counters, so any Logger handle can read
+// the whole program's totals via Counts/Suppressed, and they only need to
+// be registered under expvar once, in init below.
+var (
+	eventCounts      [log.None]uint64
+	suppressedCounts [log.None]uint64
+	droppedCounts    [log.None]uint64
+)
+
+func init() {
+	m := expvar.NewMap("log_events")
+	for lvl := log.Debug; lvl < log.None; lvl++ {
+		lvl := lvl
+		m.Set(lvl.String(), expvar.Func(func() interface{} {
+			return atomic.LoadUint64(&eventCounts[lvl])
+		}))
+		m.Set(lvl.String()+"_suppressed", expvar.Func(func() interface{} {
+			return atomic.LoadUint64(&suppressedCounts[lvl])
+		}))
+		m.Set(lvl.String()+"_dropped", expvar.Func(func() interface{} {
+			return atomic.LoadUint64(&droppedCounts[lvl])
+		}))
+	}
+}
+
+// Counts returns, for each level, the number of events emitted so far by
+// this process.
+func (l *Logger) Counts() [log.None]uint64 {
+	var counts [log.None]uint64
+	for i := range counts {
+		counts[i] = atomic.LoadUint64(&eventCounts[i])
+	}
+	return counts
+}
+
+// Suppressed is like Counts but for events dropped because they were below
+// the logger's threshold rather than actually emitted.
+func (l *Logger) Suppressed() [log.None]uint64 {
+	var counts [log.None]uint64
+	for i := range counts {
+		counts[i] = atomic.LoadUint64(&suppressedCounts[i])
+	}
+	return counts
+}
+
+// Dropped is like Counts but for events that passed the threshold yet were
+// never written because an async Logger's buffer was full and its OnFull
+// policy is DropOldest or DropNewest (see NewAsync).
+func (l *Logger) Dropped() [log.None]uint64 {
+	var counts [log.None]uint64
+	for i := range counts {
+		counts[i] = atomic.LoadUint64(&droppedCounts[i])
+	}
+	return counts
+}
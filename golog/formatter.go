@@ -0,0 +1,132 @@
+package golog
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/mehrvarz/log"
+)
+
+// Formatter renders a single log event into the bytes written to the
+// output stream. Register a custom one via NewFormatted to change how
+// events look, e.g. to emit JSON for a log aggregator instead of the
+// default human-readable line.
+type Formatter interface {
+	Format(level log.Level, ts time.Time, msg string) []byte
+}
+
+// FieldsFormatter is implemented by formatters that know how to render the
+// key/value pairs attached via Logger.With alongside the event. A Formatter
+// that doesn't implement it (e.g. a minimal custom one) simply has those
+// fields omitted from its output.
+type FieldsFormatter interface {
+	Formatter
+	FormatFields(level log.Level, ts time.Time, msg string, fields []log.Field) []byte
+}
+
+// TextFormatter is the default Formatter, producing human-readable lines,
+// e.g.:
+//
+//	18:09:15.862 INFO Connecting to the API...
+//
+// or, with WithDate set:
+//
+//	2014-04-02 18:09:15.862 INFO Connecting to the API...
+type TextFormatter struct {
+	// WithDate additionally prefixes the line with the date, as used by
+	// New/NewWriter vs. NewDate/NewDateWriter.
+	WithDate bool
+}
+
+const (
+	timeOnlyFormat = "15:04:05.000"
+	dateTimeFormat = "2006-01-02 15:04:05.000"
+)
+
+func (f TextFormatter) Format(level log.Level, ts time.Time, msg string) []byte {
+	return f.FormatFields(level, ts, msg, nil)
+}
+
+func (f TextFormatter) FormatFields(level log.Level, ts time.Time, msg string, fields []log.Field) []byte {
+	layout := timeOnlyFormat
+	if f.WithDate {
+		layout = dateTimeFormat
+	}
+	line := fmt.Sprintf("%s %s %s", ts.Format(layout), level, msg)
+	for _, field := range fields {
+		line += fmt.Sprintf(" %s=%v", field.Key, field.Value)
+	}
+	return append([]byte(line), '\n')
+}
+
+// JSONFormatter renders each event as a single line JSON object with ts,
+// level, msg and caller keys, plus one key per field attached via
+// Logger.With. It is used by stdlog.GetFromFlagsJSON and -logformat=json.
+type JSONFormatter struct{}
+
+// ownFramePrefixes lists the call frames callerLocation treats as internal
+// plumbing rather than a real call site: golog's own Logger.<Level> -> log
+// -> jsonEvent chain, plus the loggers known to wrap a golog.Logger and
+// forward a call to it without adding a frame worth reporting (buflog,
+// ratelog). A fixed skip count can't be right for both the direct and
+// every wrapped depth at once, so instead walk the stack past every frame
+// that matches one of these prefixes and report the first one that isn't.
+var ownFramePrefixes = []string{
+	"github.com/mehrvarz/log/golog.",
+	"github.com/mehrvarz/log/buflog.",
+	"github.com/mehrvarz/log/ratelog.",
+}
+
+func callerLocation() (file string, line int, ok bool) {
+	var pcs [16]uintptr
+	n := runtime.Callers(3, pcs[:]) // skip Callers, callerLocation, jsonEvent
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !isOwnFrame(frame.Function) {
+			return frame.File, frame.Line, true
+		}
+		if !more {
+			return "", 0, false
+		}
+	}
+}
+
+func isOwnFrame(function string) bool {
+	for _, prefix := range ownFramePrefixes {
+		if strings.HasPrefix(function, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f JSONFormatter) Format(level log.Level, ts time.Time, msg string) []byte {
+	return f.jsonEvent(level, ts, msg, nil)
+}
+
+func (f JSONFormatter) FormatFields(level log.Level, ts time.Time, msg string, fields []log.Field) []byte {
+	return f.jsonEvent(level, ts, msg, fields)
+}
+
+func (f JSONFormatter) jsonEvent(level log.Level, ts time.Time, msg string, fields []log.Field) []byte {
+	event := make(map[string]interface{}, 4+len(fields))
+	event["ts"] = ts.Format(time.RFC3339Nano)
+	event["level"] = level.String()
+	event["msg"] = msg
+	if file, line, ok := callerLocation(); ok {
+		event["caller"] = fmt.Sprintf("%s:%d", file, line)
+	}
+	for _, field := range fields {
+		event[field.Key] = field.Value
+	}
+
+	b, err := json.Marshal(event)
+	if err != nil {
+		return []byte(fmt.Sprintf("{\"ts\":%q,\"level\":\"ERROR\",\"msg\":\"golog: JSONFormatter: %s\"}\n", ts.Format(time.RFC3339Nano), err))
+	}
+	return append(b, '\n')
+}
@@ -0,0 +1,215 @@
+// Package golog is the default log.Logger implementation used by stdlog. It
+// writes one line per event to an io.Writer, guarded by a mutex so it is
+// safe for concurrent use from multiple goroutines.
+package golog
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mehrvarz/log"
+)
+
+var namesToLevel = map[string]log.Level{
+	"debug":     log.Debug,
+	"info":      log.Info,
+	"notice":    log.Notice,
+	"warning":   log.Warning,
+	"error":     log.Error,
+	"critical":  log.Critical,
+	"alert":     log.Alert,
+	"emergency": log.Emergency,
+	"none":      log.None,
+}
+
+// GetLevelFromName returns the log.Level matching name (case-insensitive).
+// It returns log.Info if name does not match any known level.
+func GetLevelFromName(name string) log.Level {
+	if level, ok := namesToLevel[strings.ToLower(name)]; ok {
+		return level
+	}
+	return log.Info
+}
+
+// Writer is the signature of the function used to emit an already formatted
+// line to out. The default one simply writes it; GetFromFlagsWriter lets
+// callers plug in their own, e.g. to also forward lines to a remote
+// collector.
+type Writer func(out io.Writer, b []byte, level log.Level)
+
+// FeedbackLevel is the level a Logger passes to Writer for a line written by
+// Feedback or Feedbackln, so a custom Writer (or buflog, which keys its
+// buffer entries by level) can tell a plain, undecorated line apart from a
+// leveled event. It is never used as an event's severity and never matches
+// any threshold comparison, since it sorts below Debug.
+const FeedbackLevel log.Level = -1
+
+// core holds the state shared by a Logger and every child created from it
+// via With, so that attaching fields never duplicates the mutex, the
+// destination writer or the formatter.
+type core struct {
+	mu        sync.Mutex
+	out       io.Writer
+	threshold log.Level
+	write     Writer
+	formatter Formatter
+
+	// async is non-nil for a Logger created with NewAsync and a positive
+	// bufSize; it routes writes through a background goroutine instead of
+	// the mutex-guarded path above. bufSize=0 leaves it nil, making the
+	// synchronous path the special case described on NewAsync.
+	async *async
+}
+
+// Logger is the default log.Logger implementation. Create a synchronous one
+// with New, NewDate, NewWriter, NewDateWriter or NewFormatted, or an
+// asynchronous one with NewAsync.
+type Logger struct {
+	c      *core
+	fields []log.Field
+}
+
+func defaultWrite(out io.Writer, b []byte, level log.Level) {
+	out.Write(b)
+}
+
+// New returns a Logger that writes events at or above threshold to out,
+// timestamped to millisecond precision.
+func New(out io.Writer, threshold log.Level) *Logger {
+	return NewFormatted(out, threshold, TextFormatter{})
+}
+
+// NewDate is like New but also prefixes each line with the date.
+func NewDate(out io.Writer, threshold log.Level) *Logger {
+	return NewFormatted(out, threshold, TextFormatter{WithDate: true})
+}
+
+// NewWriter is like New but routes every already-formatted line through
+// write instead of writing it to out directly.
+func NewWriter(out io.Writer, threshold log.Level, write Writer) *Logger {
+	l := New(out, threshold)
+	l.c.write = write
+	return l
+}
+
+// NewDateWriter combines NewDate and NewWriter.
+func NewDateWriter(out io.Writer, threshold log.Level, write Writer) *Logger {
+	l := NewDate(out, threshold)
+	l.c.write = write
+	return l
+}
+
+// NewFormattedWriter combines NewFormatted and NewWriter: events are
+// rendered with formatter, then handed to write instead of written to out
+// directly. buflog uses this to keep honoring a custom Formatter (e.g.
+// JSONFormatter) while still buffering lines until flushThreshold.
+func NewFormattedWriter(out io.Writer, threshold log.Level, formatter Formatter, write Writer) *Logger {
+	l := NewFormatted(out, threshold, formatter)
+	l.c.write = write
+	return l
+}
+
+// NewFormatted returns a Logger that renders events with formatter instead
+// of the default human-readable text line. This is how stdlog.GetFromFlagsJSON
+// plugs in JSONFormatter.
+func NewFormatted(out io.Writer, threshold log.Level, formatter Formatter) *Logger {
+	return &Logger{c: &core{out: out, threshold: threshold, write: defaultWrite, formatter: formatter}}
+}
+
+// With returns a child logger that carries key alongside every future
+// event, in addition to l's own fields. l itself is unaffected.
+func (l *Logger) With(key string, value interface{}) log.Logger {
+	fields := make([]log.Field, len(l.fields), len(l.fields)+1)
+	copy(fields, l.fields)
+	fields = append(fields, log.Field{Key: key, Value: value})
+	return &Logger{c: l.c, fields: fields}
+}
+
+func (l *Logger) log(level log.Level, format string, args ...interface{}) {
+	if level < l.c.threshold {
+		atomic.AddUint64(&suppressedCounts[level], 1)
+		return
+	}
+	msg := format
+	if len(args) > 0 {
+		msg = fmt.Sprintf(format, args...)
+	}
+	ts := time.Now()
+	var line []byte
+	if ff, ok := l.c.formatter.(FieldsFormatter); ok && len(l.fields) > 0 {
+		line = ff.FormatFields(level, ts, msg, l.fields)
+	} else {
+		line = l.c.formatter.Format(level, ts, msg)
+	}
+
+	if l.c.async != nil {
+		l.c.async.enqueue(level, line)
+		return
+	}
+
+	l.c.mu.Lock()
+	defer l.c.mu.Unlock()
+	l.c.write(l.c.out, line, level)
+	atomic.AddUint64(&eventCounts[level], 1)
+}
+
+// Feedback writes a plain, undecorated line straight to the destination,
+// bypassing the formatter and the threshold check the level methods apply.
+// See log.Logger.
+func (l *Logger) Feedback(format string, args ...interface{}) {
+	msg := format
+	if len(args) > 0 {
+		msg = fmt.Sprintf(format, args...)
+	}
+	l.writeRaw([]byte(msg))
+}
+
+// Feedbackln is like Feedback but appends a trailing newline, like
+// fmt.Sprintln.
+func (l *Logger) Feedbackln(args ...interface{}) {
+	l.writeRaw([]byte(fmt.Sprintln(args...)))
+}
+
+func (l *Logger) writeRaw(line []byte) {
+	if l.c.async != nil {
+		l.c.async.enqueue(FeedbackLevel, line)
+		return
+	}
+
+	l.c.mu.Lock()
+	defer l.c.mu.Unlock()
+	l.c.write(l.c.out, line, FeedbackLevel)
+}
+
+// Flush blocks until every event enqueued so far by an async Logger (see
+// NewAsync) has been written. It is a no-op for a synchronous one.
+func (l *Logger) Flush() error {
+	if l.c.async == nil {
+		return nil
+	}
+	l.c.async.flush()
+	return nil
+}
+
+// Close flushes pending events and stops the background goroutine started
+// by NewAsync. It is a no-op for a synchronous Logger.
+func (l *Logger) Close() error {
+	if l.c.async == nil {
+		return nil
+	}
+	l.c.async.stop()
+	return nil
+}
+
+func (l *Logger) Debug(format string, args ...interface{})     { l.log(log.Debug, format, args...) }
+func (l *Logger) Info(format string, args ...interface{})      { l.log(log.Info, format, args...) }
+func (l *Logger) Notice(format string, args ...interface{})    { l.log(log.Notice, format, args...) }
+func (l *Logger) Warning(format string, args ...interface{})   { l.log(log.Warning, format, args...) }
+func (l *Logger) Error(format string, args ...interface{})     { l.log(log.Error, format, args...) }
+func (l *Logger) Critical(format string, args ...interface{})  { l.log(log.Critical, format, args...) }
+func (l *Logger) Alert(format string, args ...interface{})     { l.log(log.Alert, format, args...) }
+func (l *Logger) Emergency(format string, args ...interface{}) { l.log(log.Emergency, format, args...) }
@@ -0,0 +1,65 @@
+package golog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/mehrvarz/log"
+)
+
+func TestLogRespectsThreshold(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(&buf, log.Warning)
+
+	l.Info("should be suppressed")
+	if buf.Len() != 0 {
+		t.Fatalf("Info below threshold wrote %q, want nothing", buf.String())
+	}
+
+	l.Warning("should be emitted")
+	if !strings.Contains(buf.String(), "should be emitted") {
+		t.Fatalf("Warning at threshold did not appear in %q", buf.String())
+	}
+}
+
+func TestWithPropagatesFieldsToChildAndGrandchild(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewFormatted(&buf, log.Debug, JSONFormatter{})
+
+	child := l.With("req", "abc123")
+	grandchild := child.With("user", "alice")
+
+	grandchild.Info("handled request")
+	out := buf.String()
+	for _, want := range []string{`"req":"abc123"`, `"user":"alice"`, `"msg":"handled request"`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("grandchild event %q missing %q", out, want)
+		}
+	}
+
+	buf.Reset()
+	l.Info("top-level event")
+	if strings.Contains(buf.String(), "req") || strings.Contains(buf.String(), "user") {
+		t.Errorf("l.With left fields on the parent logger: %q", buf.String())
+	}
+}
+
+func TestNewFormattedJSONvsText(t *testing.T) {
+	var jsonBuf, textBuf bytes.Buffer
+	jsonLogger := NewFormatted(&jsonBuf, log.Debug, JSONFormatter{})
+	textLogger := New(&textBuf, log.Debug)
+
+	jsonLogger.Error("boom %d", 42)
+	textLogger.Error("boom %d", 42)
+
+	if !strings.HasPrefix(strings.TrimSpace(jsonBuf.String()), "{") {
+		t.Errorf("JSONFormatter output doesn't look like JSON: %q", jsonBuf.String())
+	}
+	if strings.HasPrefix(strings.TrimSpace(textBuf.String()), "{") {
+		t.Errorf("TextFormatter output looks like JSON: %q", textBuf.String())
+	}
+	if !strings.Contains(textBuf.String(), "boom 42") || !strings.Contains(jsonBuf.String(), "boom 42") {
+		t.Errorf("formatted message missing, json=%q text=%q", jsonBuf.String(), textBuf.String())
+	}
+}
@@ -0,0 +1,157 @@
+// Package journald is a log.Logger implementation that writes directly to
+// systemd-journald's native datagram protocol, without shelling out to
+// logger(1). See systemd.journal-fields(7) and sd_journal_print(3) for the
+// wire format. It backs stdlog's -log-sink=journald flag.
+package journald
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/mehrvarz/log"
+)
+
+// socketPath is journald's well-known datagram socket, constant across
+// systemd versions.
+const socketPath = "/run/systemd/journal/socket"
+
+// priority maps this module's RFC 5424 severities onto the numeric
+// syslog 0 (emergency) - 7 (debug) scale journald expects in PRIORITY.
+var priority = map[log.Level]int{
+	log.Debug:     7,
+	log.Info:      6,
+	log.Notice:    5,
+	log.Warning:   4,
+	log.Error:     3,
+	log.Critical:  2,
+	log.Alert:     1,
+	log.Emergency: 0,
+}
+
+// Logger writes events straight to the local journald socket.
+type Logger struct {
+	conn      net.Conn
+	threshold log.Level
+	fields    []log.Field
+}
+
+// New connects to the local journald socket and returns a Logger that logs
+// events at or above threshold.
+func New(threshold log.Level) (*Logger, error) {
+	conn, err := net.Dial("unixgram", socketPath)
+	if err != nil {
+		return nil, fmt.Errorf("journald: dial: %w", err)
+	}
+	return &Logger{conn: conn, threshold: threshold}, nil
+}
+
+// With returns a child logger that carries key alongside every future
+// event. l itself is unaffected.
+func (l *Logger) With(key string, value interface{}) log.Logger {
+	fields := make([]log.Field, len(l.fields), len(l.fields)+1)
+	copy(fields, l.fields)
+	fields = append(fields, log.Field{Key: key, Value: value})
+	return &Logger{conn: l.conn, threshold: l.threshold, fields: fields}
+}
+
+// Flush is a no-op: each event is a single datagram written immediately,
+// with nothing buffered on our side to drain.
+func (l *Logger) Flush() error { return nil }
+
+// Close closes the connection to the journald socket.
+func (l *Logger) Close() error { return l.conn.Close() }
+
+// Feedback and Feedbackln send msg to journald at Info priority. journald
+// has no concept of an undecorated line outside its own field format, so
+// this is the plainest path available: a MESSAGE field and nothing else
+// this module's own level methods wouldn't already add.
+func (l *Logger) Feedback(format string, args ...interface{}) {
+	l.log(log.Info, format, args...)
+}
+
+func (l *Logger) Feedbackln(args ...interface{}) {
+	l.log(log.Info, fmt.Sprintln(args...))
+}
+
+func (l *Logger) log(level log.Level, format string, args ...interface{}) {
+	msg := format
+	if len(args) > 0 {
+		msg = fmt.Sprintf(format, args...)
+	}
+
+	var buf bytes.Buffer
+	writeField(&buf, "PRIORITY", strconv.Itoa(priority[level]))
+	writeField(&buf, "MESSAGE", msg)
+	for _, field := range l.fields {
+		writeField(&buf, fieldName(field.Key), fmt.Sprintf("%v", field.Value))
+	}
+
+	l.conn.Write(buf.Bytes())
+}
+
+// logLeveled is log gated on threshold, used by the level methods below.
+// Feedback and Feedbackln call log directly instead, since like golog's
+// Feedback they bypass the threshold check by design.
+func (l *Logger) logLeveled(level log.Level, format string, args ...interface{}) {
+	if level < l.threshold {
+		return
+	}
+	l.log(level, format, args...)
+}
+
+// writeField appends one journald field in its native protocol encoding:
+// "FIELD=value\n" for values without a newline, or
+// "FIELD\n<8-byte little-endian length><value>\n" for values that contain
+// one (e.g. a multi-line stack trace), per sd_journal_print(3).
+func writeField(buf *bytes.Buffer, name, value string) {
+	if !strings.Contains(value, "\n") {
+		buf.WriteString(name)
+		buf.WriteByte('=')
+		buf.WriteString(value)
+		buf.WriteByte('\n')
+		return
+	}
+
+	buf.WriteString(name)
+	buf.WriteByte('\n')
+	var length [8]byte
+	binary.LittleEndian.PutUint64(length[:], uint64(len(value)))
+	buf.Write(length[:])
+	buf.WriteString(value)
+	buf.WriteByte('\n')
+}
+
+// fieldName upper-cases and sanitizes key into a valid journald field name
+// ([A-Z0-9_], conventionally starting with a letter or underscore).
+func fieldName(key string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(key) {
+		if r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
+
+func (l *Logger) Debug(format string, args ...interface{}) { l.logLeveled(log.Debug, format, args...) }
+func (l *Logger) Info(format string, args ...interface{})  { l.logLeveled(log.Info, format, args...) }
+func (l *Logger) Notice(format string, args ...interface{}) {
+	l.logLeveled(log.Notice, format, args...)
+}
+func (l *Logger) Warning(format string, args ...interface{}) {
+	l.logLeveled(log.Warning, format, args...)
+}
+func (l *Logger) Error(format string, args ...interface{}) { l.logLeveled(log.Error, format, args...) }
+func (l *Logger) Critical(format string, args ...interface{}) {
+	l.logLeveled(log.Critical, format, args...)
+}
+func (l *Logger) Alert(format string, args ...interface{}) { l.logLeveled(log.Alert, format, args...) }
+func (l *Logger) Emergency(format string, args ...interface{}) {
+	l.logLeveled(log.Emergency, format, args...)
+}
@@ -0,0 +1,115 @@
+package journald
+
+import (
+	"bytes"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/mehrvarz/log"
+)
+
+func TestPriorityMapsEveryLevelOntoSyslogScale(t *testing.T) {
+	want := map[log.Level]int{
+		log.Debug:     7,
+		log.Info:      6,
+		log.Notice:    5,
+		log.Warning:   4,
+		log.Error:     3,
+		log.Critical:  2,
+		log.Alert:     1,
+		log.Emergency: 0,
+	}
+	if len(priority) != len(want) {
+		t.Fatalf("priority has %d entries, want %d", len(priority), len(want))
+	}
+	for lvl, p := range want {
+		if got, ok := priority[lvl]; !ok || got != p {
+			t.Errorf("priority[%s] = %d, %v; want %d", lvl, got, ok, p)
+		}
+	}
+}
+
+func TestWriteFieldSingleLine(t *testing.T) {
+	var buf bytes.Buffer
+	writeField(&buf, "MESSAGE", "hello world")
+	if got, want := buf.String(), "MESSAGE=hello world\n"; got != want {
+		t.Errorf("writeField = %q, want %q", got, want)
+	}
+}
+
+func TestWriteFieldMultiline(t *testing.T) {
+	var buf bytes.Buffer
+	writeField(&buf, "MESSAGE", "line one\nline two")
+	got := buf.Bytes()
+
+	if !bytes.HasPrefix(got, []byte("MESSAGE\n")) {
+		t.Fatalf("writeField multiline didn't start with the bare field name: %q", got)
+	}
+	rest := got[len("MESSAGE\n"):]
+	if len(rest) < 8 {
+		t.Fatalf("writeField multiline output too short for an 8-byte length: %q", got)
+	}
+	length := int(rest[0]) | int(rest[1])<<8 | int(rest[2])<<16 | int(rest[3])<<24
+	value := rest[8 : 8+length]
+	if string(value) != "line one\nline two" {
+		t.Errorf("writeField multiline value = %q, want %q", value, "line one\nline two")
+	}
+	if rest[8+length] != '\n' {
+		t.Errorf("writeField multiline missing trailing newline after the value")
+	}
+}
+
+func TestFieldNameSanitizes(t *testing.T) {
+	cases := map[string]string{
+		"request_id": "REQUEST_ID",
+		"req.id":     "REQ_ID",
+		"user-agent": "USER_AGENT",
+		"already_OK": "ALREADY_OK",
+	}
+	for in, want := range cases {
+		if got := fieldName(in); got != want {
+			t.Errorf("fieldName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+// datagramPriority reads one datagram off conn and returns its PRIORITY
+// field, so a threshold test doesn't need a live journald socket.
+func datagramPriority(t *testing.T, conn net.Conn) string {
+	t.Helper()
+	buf := make([]byte, 4096)
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatalf("read datagram: %v", err)
+	}
+	for _, field := range strings.Split(string(buf[:n]), "\n") {
+		if p, ok := strings.CutPrefix(field, "PRIORITY="); ok {
+			return p
+		}
+	}
+	t.Fatalf("datagram missing PRIORITY field: %q", buf[:n])
+	return ""
+}
+
+func TestLogDropsEventsBelowThreshold(t *testing.T) {
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+
+	l := &Logger{conn: client, threshold: log.Warning}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		l.Debug("below threshold, must not reach the socket")
+		l.Error("at or above threshold, must reach the socket")
+	}()
+
+	got := datagramPriority(t, server)
+	if want := strconv.Itoa(priority[log.Error]); got != want {
+		t.Errorf("first datagram PRIORITY = %q, want %q (the Error event, Debug should have been dropped)", got, want)
+	}
+	<-done
+}
@@ -0,0 +1,88 @@
+// Package log defines the Logger interface implemented by golog, buflog and
+// the other loggers of this module, along with the Level type used to
+// threshold and classify events.
+package log
+
+// Level is the severity of a log event, as defined by RFC 5424.
+type Level int
+
+// The eight RFC 5424 severities, from least to most severe, plus None which
+// is only ever used as a threshold meaning "never" (for -log) or "don't
+// buffer/flush" (for -flushlog).
+const (
+	Debug Level = iota
+	Info
+	Notice
+	Warning
+	Error
+	Critical
+	Alert
+	Emergency
+	None
+)
+
+var levelNames = [...]string{
+	Debug:     "DEBUG",
+	Info:      "INFO",
+	Notice:    "NOTICE",
+	Warning:   "WARNING",
+	Error:     "ERROR",
+	Critical:  "CRITICAL",
+	Alert:     "ALERT",
+	Emergency: "EMERGENCY",
+	None:      "NONE",
+}
+
+// String returns the upper-case name of the level, e.g. "INFO".
+func (l Level) String() string {
+	if l < Debug || l > None {
+		return "UNKNOWN"
+	}
+	return levelNames[l]
+}
+
+// Field is a single structured key/value pair attached to a Logger via
+// With. Every event logged afterwards, by that logger or any child derived
+// from it, carries the field alongside the message.
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// Logger is the interface implemented by every logger in this module. Each
+// level method formats its arguments with fmt.Sprintf semantics (except
+// that the format is used as-is when no args are given, so a stray % in it
+// is not interpreted), then logs the result at the corresponding severity.
+type Logger interface {
+	Debug(format string, args ...interface{})
+	Info(format string, args ...interface{})
+	Notice(format string, args ...interface{})
+	Warning(format string, args ...interface{})
+	Error(format string, args ...interface{})
+	Critical(format string, args ...interface{})
+	Alert(format string, args ...interface{})
+	Emergency(format string, args ...interface{})
+
+	// With returns a child logger that carries key/value alongside every
+	// future event, in addition to any fields the parent already carries.
+	// The receiver is left untouched.
+	With(key string, value interface{}) Logger
+
+	// Feedback and Feedbackln write directly to the logger's destination,
+	// without the timestamp/level decoration the methods above add. They
+	// are for CLI programs that mix user-facing output (progress,
+	// prompts, results) with leveled logs on the same stream. Feedbackln
+	// appends a trailing newline like fmt.Println; Feedback does not add
+	// one, like fmt.Printf.
+	Feedback(format string, args ...interface{})
+	Feedbackln(args ...interface{})
+
+	// Flush blocks until every event submitted so far has been written.
+	// It is a no-op for loggers that write synchronously.
+	Flush() error
+
+	// Close flushes pending events and releases any resources the logger
+	// holds (a background goroutine, an open connection). The logger
+	// must not be used after Close.
+	Close() error
+}
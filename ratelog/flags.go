@@ -0,0 +1,29 @@
+package ratelog
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseRate parses the "-log-rate" flag syntax, "N/duration" (e.g.
+// "100/1s"), into a Rate.
+func ParseRate(s string) (Rate, error) {
+	n, interval, found := strings.Cut(s, "/")
+	if !found {
+		return Rate{}, fmt.Errorf("ratelog: invalid rate %q, want N/duration (e.g. 100/1s)", s)
+	}
+
+	count, err := strconv.Atoi(n)
+	if err != nil {
+		return Rate{}, fmt.Errorf("ratelog: invalid rate %q: %w", s, err)
+	}
+
+	d, err := time.ParseDuration(interval)
+	if err != nil {
+		return Rate{}, fmt.Errorf("ratelog: invalid rate %q: %w", s, err)
+	}
+
+	return Rate{N: count, Interval: d}, nil
+}
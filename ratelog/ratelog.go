@@ -0,0 +1,239 @@
+// Package ratelog wraps a log.Logger to protect it against a hot error path
+// flooding disks (or a remote sink) in a long-running service: events
+// beyond a configurable rate are dropped per level, and events logged with
+// the same format string in a tight loop are collapsed into a single
+// "[repeated N times]" summary. It backs stdlog's -log-rate and -log-dedup flags.
+package ratelog
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+	"unsafe"
+
+	"github.com/mehrvarz/log"
+)
+
+// Rate is a token-bucket limit of N events per Interval.
+type Rate struct {
+	N        int
+	Interval time.Duration
+}
+
+// Options configures a Logger. The zero value of either field disables
+// that feature.
+type Options struct {
+	// Rate, if Rate.N > 0, drops events beyond Rate.N per Rate.Interval,
+	// per level.
+	Rate Rate
+
+	// Dedup, if > 0, logs the first event for a format string right away
+	// and collapses the ones logged with the same format string within
+	// this window after it into one summary line emitted when the window
+	// closes.
+	Dedup time.Duration
+}
+
+// dispatch maps each level onto the matching log.Logger method, so New's
+// caller doesn't have to.
+var dispatch = map[log.Level]func(log.Logger, string, ...interface{}){
+	log.Debug:     log.Logger.Debug,
+	log.Info:      log.Logger.Info,
+	log.Notice:    log.Logger.Notice,
+	log.Warning:   log.Logger.Warning,
+	log.Error:     log.Logger.Error,
+	log.Critical:  log.Logger.Critical,
+	log.Alert:     log.Logger.Alert,
+	log.Emergency: log.Logger.Emergency,
+}
+
+type dedupEntry struct {
+	mu      sync.Mutex
+	inner   log.Logger
+	level   log.Level
+	lastMsg string
+	count   int
+}
+
+// state is shared by a Logger and every child created from it via With, so
+// rate limiting and dedup windows apply across the whole family rather
+// than resetting per child.
+type state struct {
+	rate Rate
+
+	mu     sync.Mutex
+	tokens [log.None]float64
+	last   [log.None]time.Time
+
+	dedupWindow time.Duration
+	dedupMu     sync.Mutex
+	dedup       map[uintptr]*dedupEntry
+}
+
+// Logger is a log.Logger that rate-limits and deduplicates events before
+// passing the survivors through to inner.
+type Logger struct {
+	s     *state
+	inner log.Logger
+}
+
+// New returns a Logger that applies opts to every event before logging it
+// to inner.
+func New(inner log.Logger, opts Options) *Logger {
+	s := &state{
+		rate:        opts.Rate,
+		dedupWindow: opts.Dedup,
+		dedup:       make(map[uintptr]*dedupEntry),
+	}
+	now := time.Now()
+	for i := range s.tokens {
+		s.tokens[i] = float64(opts.Rate.N)
+		s.last[i] = now
+	}
+	return &Logger{s: s, inner: inner}
+}
+
+// With returns a child logger that carries key alongside every future
+// event. The child shares l's rate limit and dedup state.
+func (l *Logger) With(key string, value interface{}) log.Logger {
+	return &Logger{s: l.s, inner: l.inner.With(key, value)}
+}
+
+// counter is implemented by loggers, such as golog.Logger and buflog.Logger,
+// that track per-level event counts.
+type counter interface {
+	Counts() [log.None]uint64
+	Suppressed() [log.None]uint64
+}
+
+// Counts and Suppressed forward to inner when it tracks counts, so wrapping
+// a counting logger with ratelog doesn't hide its counters. Events this
+// Logger itself drops (rate-limited, or collapsed into an open dedup
+// window) never reach inner and so aren't reflected in either count.
+func (l *Logger) Counts() [log.None]uint64 {
+	if c, ok := l.inner.(counter); ok {
+		return c.Counts()
+	}
+	return [log.None]uint64{}
+}
+
+func (l *Logger) Suppressed() [log.None]uint64 {
+	if c, ok := l.inner.(counter); ok {
+		return c.Suppressed()
+	}
+	return [log.None]uint64{}
+}
+
+// Feedback and Feedbackln forward straight to inner: they are plain,
+// user-facing output rather than log events, so neither the rate limit nor
+// the dedup window applies to them.
+func (l *Logger) Feedback(format string, args ...interface{}) { l.inner.Feedback(format, args...) }
+func (l *Logger) Feedbackln(args ...interface{})              { l.inner.Feedbackln(args...) }
+
+// Flush and Close forward to inner. Pending dedup windows are not flushed;
+// their summary lines are best-effort and may be lost on shutdown.
+func (l *Logger) Flush() error { return l.inner.Flush() }
+func (l *Logger) Close() error { return l.inner.Close() }
+
+func (l *Logger) log(level log.Level, format string, args ...interface{}) {
+	if !l.s.allow(level) {
+		return
+	}
+	if l.s.dedupWindow > 0 && l.s.suppress(level, format, args, l.inner) {
+		return
+	}
+	dispatch[level](l.inner, format, args...)
+}
+
+// allow implements a lazily-refilled token bucket per level: rate.N tokens
+// are available every rate.Interval, refilled in proportion to elapsed
+// time so no background goroutine is needed.
+func (s *state) allow(level log.Level) bool {
+	if s.rate.N <= 0 {
+		return true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if elapsed := now.Sub(s.last[level]); elapsed > 0 {
+		s.tokens[level] += elapsed.Seconds() / s.rate.Interval.Seconds() * float64(s.rate.N)
+		if s.tokens[level] > float64(s.rate.N) {
+			s.tokens[level] = float64(s.rate.N)
+		}
+		s.last[level] = now
+	}
+
+	if s.tokens[level] < 1 {
+		return false
+	}
+	s.tokens[level]--
+	return true
+}
+
+// suppress reports whether event (level, format, args) should be collapsed
+// into a running dedup window instead of logged immediately. The window is
+// keyed by the format string's data pointer rather than its formatted
+// result, which is O(1) and safe under high load: callers almost always
+// pass the same string literal for a given log statement, so the pointer
+// is stable across calls to that statement without ever formatting it.
+func (s *state) suppress(level log.Level, format string, args []interface{}, inner log.Logger) bool {
+	key := formatKey(format)
+
+	s.dedupMu.Lock()
+	entry, open := s.dedup[key]
+	if !open {
+		entry = &dedupEntry{inner: inner, level: level}
+		s.dedup[key] = entry
+	}
+	s.dedupMu.Unlock()
+
+	entry.mu.Lock()
+	entry.count++
+	entry.lastMsg = fmt.Sprintf(format, args...)
+	entry.mu.Unlock()
+
+	if open {
+		return true
+	}
+
+	time.AfterFunc(s.dedupWindow, func() { s.closeWindow(key) })
+	return false
+}
+
+func (s *state) closeWindow(key uintptr) {
+	s.dedupMu.Lock()
+	entry, ok := s.dedup[key]
+	if ok {
+		delete(s.dedup, key)
+	}
+	s.dedupMu.Unlock()
+	if !ok {
+		return
+	}
+
+	entry.mu.Lock()
+	repeats, msg := entry.count-1, entry.lastMsg
+	entry.mu.Unlock()
+
+	if repeats > 0 {
+		dispatch[entry.level](entry.inner, "[repeated %d times in %s] %s", repeats, s.dedupWindow, msg)
+	}
+}
+
+// formatKey returns format's backing array address, used as an O(1),
+// content-independent identity for the call site that produced it.
+func formatKey(format string) uintptr {
+	return (*reflect.StringHeader)(unsafe.Pointer(&format)).Data
+}
+
+func (l *Logger) Debug(format string, args ...interface{})     { l.log(log.Debug, format, args...) }
+func (l *Logger) Info(format string, args ...interface{})      { l.log(log.Info, format, args...) }
+func (l *Logger) Notice(format string, args ...interface{})    { l.log(log.Notice, format, args...) }
+func (l *Logger) Warning(format string, args ...interface{})   { l.log(log.Warning, format, args...) }
+func (l *Logger) Error(format string, args ...interface{})     { l.log(log.Error, format, args...) }
+func (l *Logger) Critical(format string, args ...interface{})  { l.log(log.Critical, format, args...) }
+func (l *Logger) Alert(format string, args ...interface{})     { l.log(log.Alert, format, args...) }
+func (l *Logger) Emergency(format string, args ...interface{}) { l.log(log.Emergency, format, args...) }
@@ -0,0 +1,144 @@
+package ratelog
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mehrvarz/log"
+)
+
+// record is one captured call to recordingLogger.
+type record struct {
+	level log.Level
+	msg   string
+}
+
+// recordingLogger is a minimal log.Logger test double that records every
+// event passed through to it, so tests can assert on exactly what ratelog
+// let through.
+type recordingLogger struct {
+	mu      sync.Mutex
+	records []record
+	fields  []log.Field
+}
+
+func (r *recordingLogger) add(level log.Level, format string, args []interface{}) {
+	msg := format
+	if len(args) > 0 {
+		msg = fmt.Sprintf(format, args...)
+	}
+	r.mu.Lock()
+	r.records = append(r.records, record{level: level, msg: msg})
+	r.mu.Unlock()
+}
+
+func (r *recordingLogger) snapshot() []record {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]record, len(r.records))
+	copy(out, r.records)
+	return out
+}
+
+func (r *recordingLogger) Debug(format string, args ...interface{}) { r.add(log.Debug, format, args) }
+func (r *recordingLogger) Info(format string, args ...interface{})  { r.add(log.Info, format, args) }
+func (r *recordingLogger) Notice(format string, args ...interface{}) {
+	r.add(log.Notice, format, args)
+}
+func (r *recordingLogger) Warning(format string, args ...interface{}) {
+	r.add(log.Warning, format, args)
+}
+func (r *recordingLogger) Error(format string, args ...interface{}) { r.add(log.Error, format, args) }
+func (r *recordingLogger) Critical(format string, args ...interface{}) {
+	r.add(log.Critical, format, args)
+}
+func (r *recordingLogger) Alert(format string, args ...interface{}) { r.add(log.Alert, format, args) }
+func (r *recordingLogger) Emergency(format string, args ...interface{}) {
+	r.add(log.Emergency, format, args)
+}
+
+func (r *recordingLogger) With(key string, value interface{}) log.Logger {
+	fields := make([]log.Field, len(r.fields), len(r.fields)+1)
+	copy(fields, r.fields)
+	fields = append(fields, log.Field{Key: key, Value: value})
+	return &recordingLogger{fields: fields}
+}
+
+func (r *recordingLogger) Feedback(format string, args ...interface{}) {}
+func (r *recordingLogger) Feedbackln(args ...interface{})              {}
+func (r *recordingLogger) Flush() error                                { return nil }
+func (r *recordingLogger) Close() error                                { return nil }
+
+func TestRateLimitsEventsPerLevel(t *testing.T) {
+	inner := &recordingLogger{}
+	l := New(inner, Options{Rate: Rate{N: 2, Interval: time.Hour}})
+
+	for i := 0; i < 5; i++ {
+		l.Error("event %d", i)
+	}
+
+	if got := len(inner.snapshot()); got != 2 {
+		t.Fatalf("got %d events through, want 2 (rate limit N=2)", got)
+	}
+}
+
+func TestRateLimitIsPerLevel(t *testing.T) {
+	inner := &recordingLogger{}
+	l := New(inner, Options{Rate: Rate{N: 1, Interval: time.Hour}})
+
+	l.Error("error event")
+	l.Warning("warning event")
+
+	if got := len(inner.snapshot()); got != 2 {
+		t.Fatalf("got %d events through, want 2 (limit is per level, not global)", got)
+	}
+}
+
+func TestDedupCollapsesRepeatsIntoSummary(t *testing.T) {
+	inner := &recordingLogger{}
+	l := New(inner, Options{Dedup: 20 * time.Millisecond})
+
+	for i := 0; i < 4; i++ {
+		l.Warning("flaky thing happened %d", i)
+	}
+
+	// The first event in the window opens it and logs immediately; the
+	// rest are collapsed until the window closes.
+	records := inner.snapshot()
+	if len(records) != 1 {
+		t.Fatalf("got %d immediate events, want 1 (first event in a dedup window)", len(records))
+	}
+
+	time.Sleep(40 * time.Millisecond)
+
+	records = inner.snapshot()
+	if len(records) != 2 {
+		t.Fatalf("got %d events after window close, want 2 (first event + summary)", len(records))
+	}
+	summary := records[1].msg
+	if want := "[repeated 3 times"; !strings.Contains(summary, want) {
+		t.Errorf("summary %q missing %q", summary, want)
+	}
+	if !strings.Contains(summary, "flaky thing happened 3") {
+		t.Errorf("summary %q missing the last message", summary)
+	}
+}
+
+func TestWithPropagatesRateState(t *testing.T) {
+	inner := &recordingLogger{}
+	l := New(inner, Options{Rate: Rate{N: 1, Interval: time.Hour}})
+	child := l.With("req", "abc123")
+
+	l.Error("from parent")
+	child.Error("from child") // shares the parent's token bucket, so this is dropped
+
+	// recordingLogger.With returns a separate recorder, so count what
+	// reached the child's recorder as well as the parent's.
+	childInner := child.(*Logger).inner.(*recordingLogger)
+	if got := len(inner.snapshot()) + len(childInner.snapshot()); got != 1 {
+		t.Fatalf("got %d events through, want 1 (child shares parent's rate limit)", got)
+	}
+}
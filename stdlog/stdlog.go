@@ -35,6 +35,36 @@
 //         in the memory. Once a log event is at or above this level, it
 //         outputs all logs in memory as well as the future log events. This
 //         feature should not be used with long-running processes.
+//     -logformat=
+//         Selects the line format used by GetFromFlagsJSON ("json" or
+//         "text"); left empty, it logs JSON. Ignored by the other
+//         constructors, which always log text.
+//     -log-sink=stdout
+//         Selects where logs go: "stdout", "stderr", "syslog" or
+//         "journald". The latter two hand off to the syslog/journald
+//         subpackages, which still honor -log but ignore -stderr,
+//         -logformat and -flushlog.
+//     -syslog-addr=
+//         Address of the syslog server to dial (e.g. "localhost:514") when
+//         -log-sink=syslog. Left empty, it dials the local syslog daemon.
+//     -log-counts-addr=
+//         If set, serves a /metrics endpoint at this address with
+//         Prometheus-style per-level log event counts, so dashboards can
+//         alert on error-rate spikes without parsing log output.
+//     -log-rate=
+//         If set to N/duration (e.g. "100/1s"), drops events beyond N per
+//         duration, per level, to protect against a hot error path
+//         flooding the log.
+//     -log-dedup=
+//         If set to a duration (e.g. "5s"), collapses log statements
+//         repeated within that window into a single
+//         "[repeated N times in D] ..." summary line.
+//     -log-async=0
+//         If set to a positive buffer size, GetFromFlags dispatches writes
+//         through a background goroutine instead of blocking the caller
+//         for the duration of the write. Call Flush or Close on shutdown
+//         to drain pending events. Only honored by GetFromFlags, and
+//         ignored there when -flushlog or a syslog/journald sink is used.
 //
 // The available levels are the eight ones described in RFC 5424 (debug, info,
 // notice, warning, error, critical, alert, emergency) and none.
@@ -52,12 +82,20 @@ package stdlog
 
 import (
 	"flag"
+	"fmt"
 	"io"
+	"net/http"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/mehrvarz/log"
 	"github.com/mehrvarz/log/buflog"
 	"github.com/mehrvarz/log/golog"
+	"github.com/mehrvarz/log/journald"
+	"github.com/mehrvarz/log/ratelog"
+	"github.com/mehrvarz/log/syslog"
 )
 
 var (
@@ -65,6 +103,13 @@ var (
 	thresholdName      *string
 	logToStderr        *bool
 	flushThresholdName *string
+	logFormatName      *string
+	logSinkName        *string
+	syslogAddr         *string
+	logCountsAddr      *string
+	logRateName        *string
+	logDedupName       *string
+	logAsyncSize       *int
 )
 
 // GetFromFlags returns the logger defined by the command-line flags. This
@@ -80,18 +125,29 @@ func GetFromFlags() log.Logger {
 	threshold := golog.GetLevelFromName(*thresholdName)
 	thresholdName = nil
 
-	out := getStream(*logToStderr)
+	if sink, ok := sinkFromFlags(threshold); ok {
+		logger = sink
+		logToStderr = nil
+		flushThresholdName = nil
+		logger = wrapRateFromFlags(logger)
+		serveCountsFromFlags(logger)
+		return logger
+	}
+
+	out := getStream(*logToStderr || *logSinkName == "stderr")
 	logToStderr = nil
 
 	flushThreshold := golog.GetLevelFromName(*flushThresholdName)
 	flushThresholdName = nil
 
 	if flushThreshold == log.None {
-		logger = golog.New(out, threshold)
+		logger = gologFromFlags(out, threshold)
 	} else {
 		logger = buflog.New(out, threshold, flushThreshold)
 	}
 
+	logger = wrapRateFromFlags(logger)
+	serveCountsFromFlags(logger)
 	return logger
 }
 
@@ -106,7 +162,16 @@ func GetFromFlagsDate() log.Logger {
 	threshold := golog.GetLevelFromName(*thresholdName)
 	thresholdName = nil
 
-	out := getStream(*logToStderr)
+	if sink, ok := sinkFromFlags(threshold); ok {
+		logger = sink
+		logToStderr = nil
+		flushThresholdName = nil
+		logger = wrapRateFromFlags(logger)
+		serveCountsFromFlags(logger)
+		return logger
+	}
+
+	out := getStream(*logToStderr || *logSinkName == "stderr")
 	logToStderr = nil
 
 	flushThreshold := golog.GetLevelFromName(*flushThresholdName)
@@ -118,6 +183,8 @@ func GetFromFlagsDate() log.Logger {
 		logger = buflog.New(out, threshold, flushThreshold)
 	}
 
+	logger = wrapRateFromFlags(logger)
+	serveCountsFromFlags(logger)
 	return logger
 }
 
@@ -133,7 +200,16 @@ func GetFromFlagsWriter(myWriter func(io.Writer, []byte, log.Level)) log.Logger
 	threshold := golog.GetLevelFromName(*thresholdName)
 	thresholdName = nil
 
-	out := getStream(*logToStderr)
+	if sink, ok := sinkFromFlags(threshold); ok {
+		logger = sink
+		logToStderr = nil
+		flushThresholdName = nil
+		logger = wrapRateFromFlags(logger)
+		serveCountsFromFlags(logger)
+		return logger
+	}
+
+	out := getStream(*logToStderr || *logSinkName == "stderr")
 	logToStderr = nil
 
 	flushThreshold := golog.GetLevelFromName(*flushThresholdName)
@@ -145,6 +221,8 @@ func GetFromFlagsWriter(myWriter func(io.Writer, []byte, log.Level)) log.Logger
 		logger = buflog.New(out, threshold, flushThreshold)
 	}
 
+	logger = wrapRateFromFlags(logger)
+	serveCountsFromFlags(logger)
 	return logger
 }
 
@@ -160,7 +238,16 @@ func GetFromFlagsDateWriter(myWriter func(io.Writer, []byte, log.Level)) log.Log
 	threshold := golog.GetLevelFromName(*thresholdName)
 	thresholdName = nil
 
-	out := getStream(*logToStderr)
+	if sink, ok := sinkFromFlags(threshold); ok {
+		logger = sink
+		logToStderr = nil
+		flushThresholdName = nil
+		logger = wrapRateFromFlags(logger)
+		serveCountsFromFlags(logger)
+		return logger
+	}
+
+	out := getStream(*logToStderr || *logSinkName == "stderr")
 	logToStderr = nil
 
 	flushThreshold := golog.GetLevelFromName(*flushThresholdName)
@@ -172,13 +259,201 @@ func GetFromFlagsDateWriter(myWriter func(io.Writer, []byte, log.Level)) log.Log
 		logger = buflog.New(out, threshold, flushThreshold)
 	}
 
+	logger = wrapRateFromFlags(logger)
+	serveCountsFromFlags(logger)
+	return logger
+}
+
+// GetFromFlagsJSON is like GetFromFlags but renders each event as a JSON
+// object (ts, level, msg, caller) instead of a human-readable line, as
+// expected by most log aggregators. The -logformat flag can still force
+// plain text back on (-logformat=text) without touching the code.
+func GetFromFlagsJSON() log.Logger {
+	if logger != nil {
+		return logger
+	}
+	if !flag.Parsed() {
+		flag.Parse()
+	}
+
+	threshold := golog.GetLevelFromName(*thresholdName)
+	thresholdName = nil
+
+	if sink, ok := sinkFromFlags(threshold); ok {
+		logger = sink
+		logToStderr = nil
+		flushThresholdName = nil
+		logger = wrapRateFromFlags(logger)
+		serveCountsFromFlags(logger)
+		return logger
+	}
+
+	out := getStream(*logToStderr || *logSinkName == "stderr")
+	logToStderr = nil
+
+	flushThreshold := golog.GetLevelFromName(*flushThresholdName)
+	flushThresholdName = nil
+
+	formatter := formatterFromFlags(golog.JSONFormatter{})
+
+	if flushThreshold == log.None {
+		logger = golog.NewFormatted(out, threshold, formatter)
+	} else {
+		logger = buflog.NewFormatted(out, threshold, flushThreshold, formatter)
+	}
+
+	logger = wrapRateFromFlags(logger)
+	serveCountsFromFlags(logger)
 	return logger
 }
 
+// gologFromFlags returns an async golog.Logger when -log-async names a
+// positive buffer size, or the plain synchronous one otherwise.
+func gologFromFlags(out io.Writer, threshold log.Level) *golog.Logger {
+	if bufSize := *logAsyncSize; bufSize > 0 {
+		return golog.NewAsync(out, threshold, bufSize, golog.Block)
+	}
+	return golog.New(out, threshold)
+}
+
+// formatterFromFlags returns defaultFormatter, unless -logformat explicitly
+// names the other known format.
+func formatterFromFlags(defaultFormatter golog.Formatter) golog.Formatter {
+	switch *logFormatName {
+	case "json":
+		return golog.JSONFormatter{}
+	case "text":
+		return golog.TextFormatter{}
+	default:
+		return defaultFormatter
+	}
+}
+
+// sinkFromFlags builds the logger for -log-sink=syslog or -log-sink=journald,
+// honoring threshold exactly as the out-based constructors below do. It
+// reports ok=false for "", "stdout" and "stderr", which are left to the
+// existing out-based construction below, and on a connection failure, in
+// which case it falls back to that same stdout/stderr path after warning.
+func sinkFromFlags(threshold log.Level) (log.Logger, bool) {
+	switch *logSinkName {
+	case "syslog":
+		network := ""
+		if *syslogAddr != "" {
+			network = "udp"
+		}
+		l, err := syslog.New(network, *syslogAddr, filepath.Base(os.Args[0]), threshold)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "stdlog: %s, falling back to stdout\n", err)
+			return nil, false
+		}
+		return l, true
+	case "journald":
+		l, err := journald.New(threshold)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "stdlog: %s, falling back to stdout\n", err)
+			return nil, false
+		}
+		return l, true
+	default:
+		return nil, false
+	}
+}
+
+// wrapRateFromFlags wraps l with ratelog if -log-rate or -log-dedup is set,
+// otherwise it returns l unchanged.
+func wrapRateFromFlags(l log.Logger) log.Logger {
+	rate, hasRate := rateFromFlags()
+	dedup := dedupFromFlags()
+	if !hasRate && dedup == 0 {
+		return l
+	}
+	return ratelog.New(l, ratelog.Options{Rate: rate, Dedup: dedup})
+}
+
+func rateFromFlags() (ratelog.Rate, bool) {
+	if *logRateName == "" {
+		return ratelog.Rate{}, false
+	}
+	rate, err := ratelog.ParseRate(*logRateName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "stdlog: %s, ignoring -log-rate\n", err)
+		return ratelog.Rate{}, false
+	}
+	return rate, true
+}
+
+func dedupFromFlags() time.Duration {
+	if *logDedupName == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(*logDedupName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "stdlog: invalid -log-dedup %q: %s, ignoring\n", *logDedupName, err)
+		return 0
+	}
+	return d
+}
+
+// counter is implemented by loggers (currently golog.Logger, buflog.Logger
+// and ratelog.Logger) that track or forward per-level event counts.
+type counter interface {
+	Counts() [log.None]uint64
+	Suppressed() [log.None]uint64
+}
+
+// serveCountsFromFlags starts the -log-counts-addr HTTP handler, if set,
+// exposing l's per-level counts (when l is a counter) as Prometheus text.
+func serveCountsFromFlags(l log.Logger) {
+	if *logCountsAddr == "" {
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		c, ok := l.(counter)
+		if !ok {
+			http.Error(w, "stdlog: this logger does not expose counts", http.StatusNotImplemented)
+			return
+		}
+		writeMetrics(w, c)
+	})
+
+	addr := *logCountsAddr
+	go func() {
+		if err := http.ListenAndServe(addr, mux); err != nil {
+			fmt.Fprintf(os.Stderr, "stdlog: -log-counts-addr: %s\n", err)
+		}
+	}()
+}
+
+// writeMetrics renders c's counts in the Prometheus text exposition format.
+func writeMetrics(w io.Writer, c counter) {
+	fmt.Fprintln(w, "# HELP log_events_total Log events emitted, by level.")
+	fmt.Fprintln(w, "# TYPE log_events_total counter")
+	counts := c.Counts()
+	for lvl := log.Debug; lvl < log.None; lvl++ {
+		fmt.Fprintf(w, "log_events_total{level=%q} %d\n", strings.ToLower(lvl.String()), counts[lvl])
+	}
+
+	fmt.Fprintln(w, "# HELP log_events_suppressed_total Log events suppressed by the threshold, by level.")
+	fmt.Fprintln(w, "# TYPE log_events_suppressed_total counter")
+	suppressed := c.Suppressed()
+	for lvl := log.Debug; lvl < log.None; lvl++ {
+		fmt.Fprintf(w, "log_events_suppressed_total{level=%q} %d\n", strings.ToLower(lvl.String()), suppressed[lvl])
+	}
+}
+
 func init() {
 	thresholdName = flag.String("log", "info", "sets the logging threshold")
 	logToStderr = flag.Bool("stderr", false, "outputs to standard error (stderr)")
 	flushThresholdName = flag.String("flushlog", "none", "sets the flush trigger level")
+	logFormatName = flag.String("logformat", "", "sets the log line format (text, json), overriding the constructor's default")
+	logSinkName = flag.String("log-sink", "stdout", "sets the log sink (stdout, stderr, syslog, journald)")
+	syslogAddr = flag.String("syslog-addr", "", "syslog server address (empty dials the local syslog daemon)")
+	logCountsAddr = flag.String("log-counts-addr", "", "if set, serves per-level log event counts as Prometheus text at this address")
+	logRateName = flag.String("log-rate", "", "if set (N/duration, e.g. 100/1s), drops events beyond N per duration per level")
+	logDedupName = flag.String("log-dedup", "", "if set (a duration, e.g. 5s), collapses repeated identical log statements into a summary line")
+	logAsyncSize = flag.Int("log-async", 0, "if set to a positive buffer size, writes are dispatched through a background goroutine instead of blocking the caller; honored by GetFromFlags only")
 }
 
 // Stubbed out for testing.
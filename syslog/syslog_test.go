@@ -0,0 +1,59 @@
+package syslog
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mehrvarz/log"
+)
+
+func TestLogFuncsCoverEveryLevel(t *testing.T) {
+	for lvl := log.Debug; lvl <= log.Emergency; lvl++ {
+		fn, ok := logFuncs[lvl]
+		if !ok {
+			t.Errorf("logFuncs has no entry for %s", lvl)
+			continue
+		}
+		if fn == nil {
+			t.Errorf("logFuncs[%s] is nil", lvl)
+		}
+	}
+	if _, ok := logFuncs[log.None]; ok {
+		t.Error("logFuncs should not map the None threshold sentinel to a priority")
+	}
+	if len(logFuncs) != 8 {
+		t.Errorf("logFuncs has %d entries, want 8 (one per RFC 5424 severity)", len(logFuncs))
+	}
+}
+
+func TestLogFuncsMapToDistinctPriorities(t *testing.T) {
+	seen := make(map[uintptr]log.Level)
+	for lvl, fn := range logFuncs {
+		ptr := reflect.ValueOf(fn).Pointer()
+		if other, ok := seen[ptr]; ok {
+			t.Errorf("%s and %s share the same *syslog.Writer method", lvl, other)
+		}
+		seen[ptr] = lvl
+	}
+}
+
+// Exercising a connected Logger needs a live syslog daemon, which this
+// sandbox doesn't have, but the threshold check in log() runs before w is
+// ever touched: a Logger with a nil w must not panic for an event below
+// threshold, and must panic dereferencing that nil w for one at or above
+// it, proving log() gated on threshold rather than always dispatching.
+func TestLogDropsEventsBelowThreshold(t *testing.T) {
+	l := &Logger{threshold: log.Warning}
+	l.Debug("below threshold, must return before touching w")
+	l.Info("below threshold, must return before touching w")
+}
+
+func TestLogDispatchesEventsAtOrAboveThreshold(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("want a panic dereferencing the nil *syslog.Writer: log() should have dispatched this at-threshold event")
+		}
+	}()
+	l := &Logger{threshold: log.Warning}
+	l.Warning("at threshold, should reach logFuncs and the nil writer")
+}